@@ -20,6 +20,12 @@ type GeoPoint struct {
 // Default value of first byte in .smy file
 const smyInitFlag int16 = 0x01
 
+// smyVersionRoundabout marks a .smy file whose companion .tinfo entries may use
+// Waypoint.extra and the roundabout/u-turn/merge/destination DirectionCode* values.
+// It is carried in the high byte of smyFlag; the low byte always stays smyInitFlag, so
+// readers that only check that byte keep parsing these files unmodified.
+const smyVersionRoundabout uint8 = 0x02
+
 // BrytonSmy content and layout of .smy bryton file
 type BrytonSmy struct {
 	smyFlag         int16
@@ -43,12 +49,23 @@ const (
 	DirectionCodeSlightRight uint8 = 0x04
 	DirectionCodeRight       uint8 = 0x02
 	DirectionCodeCloseRight  uint8 = 0x06
+
+	// DirectionCodeRoundabout requires Waypoint.extra to carry the exit number (1-N),
+	// see testdata/direction_codes_probe.md
+	DirectionCodeRoundabout         uint8 = 0x08
+	DirectionCodeUTurn              uint8 = 0x09
+	DirectionCodeMerge              uint8 = 0x0A
+	DirectionCodeDestinationReached uint8 = 0x0B
 )
 
 // Waypoint represents entry used by Bryton device
 type Waypoint struct {
-	coordinateIndex     uint16
-	directionCode       uint8
+	coordinateIndex uint16
+	directionCode   uint8
+
+	// extra carries the DirectionCodeRoundabout exit number; unused (0x00) otherwise
+	extra uint8
+
 	distance            uint16
 	timeSec             uint16
 	waypointDescription [32]uint8
@@ -62,6 +79,11 @@ type BrytonData struct {
 	smy   BrytonSmy
 	track BrytonTrack
 	tinfo BrytonTinfo
+
+	// trackTimestamps holds the GPX timestamp of each track point, parallel to track.
+	// Filled by ImportGpx for every track point (zero time.Time where the source has no
+	// timestamp); used by GenerateTurns to fill Waypoint.timeSec.
+	trackTimestamps []time.Time
 }
 
 // Export BrytonSmy structure to .smy file
@@ -70,7 +92,11 @@ func (s *BrytonSmy) Export(outFileName string) error {
 	var err error
 	var binaryBuffer bytes.Buffer
 
-	layout := []interface{}{int16(0x01), s.coordinateCount, s.bboxLatNe, s.bboxLatSw, s.bboxLonNe, s.bboxLonSw, s.totalDst}
+	// Low byte is always smyInitFlag so readers that only check it keep working; the high
+	// byte carries whatever format version the caller set on s.smyFlag.
+	flag := smyInitFlag | (s.smyFlag &^ 0xFF)
+
+	layout := []interface{}{flag, s.coordinateCount, s.bboxLatNe, s.bboxLatSw, s.bboxLonNe, s.bboxLonSw, s.totalDst}
 
 	for _, entry := range layout {
 		err = binary.Write(&binaryBuffer, binary.LittleEndian, entry)
@@ -134,13 +160,13 @@ func (t BrytonTinfo) Export(outFileName string) error {
 
 		// 2byte - coordinate index
 		// 1byte - direction
-		// 1byte - reserved 0x00
+		// 1byte - extra (DirectionCodeRoundabout exit number, 0x00 otherwise)
 		// 2byte - distance
 		// 2byte - reserved 0x00 0x00
 		// 2byte - time
 		// 2byte - reserved 0x00 0x00
 		// 32byte - description
-		layout := []interface{}{tinfoEntry.coordinateIndex, tinfoEntry.directionCode, uint8(0x00), tinfoEntry.distance, uint16(0x00),
+		layout := []interface{}{tinfoEntry.coordinateIndex, tinfoEntry.directionCode, tinfoEntry.extra, tinfoEntry.distance, uint16(0x00),
 			tinfoEntry.timeSec, uint16(0x00), tinfoEntry.waypointDescription}
 
 		for _, entry := range layout {
@@ -182,19 +208,60 @@ func storeToFile(buf bytes.Buffer, outFileName string) error {
 // Export BrytonData structure to .smy .track and .tinfo files
 func (d *BrytonData) Export(outFileName string) {
 
+	if d.tinfo.usesRoundabouts() {
+		d.smy.smyFlag = int16(smyVersionRoundabout) << 8
+	}
+
 	d.smy.Export(outFileName)
 	d.track.Export(outFileName)
 	d.tinfo.Export(outFileName)
 }
 
+// usesRoundabouts reports whether t contains any waypoint relying on the roundabout
+// exit number or the other DirectionCode* values added alongside it
+func (t BrytonTinfo) usesRoundabouts() bool {
+	for _, wpt := range t {
+		switch wpt.directionCode {
+		case DirectionCodeRoundabout, DirectionCodeUTurn, DirectionCodeMerge, DirectionCodeDestinationReached:
+			return true
+		}
+	}
+
+	return false
+}
+
 // Strips extension from in filename and adds passed as argument
 func adjustFilename(in string, extension string) string {
 	out := strings.Split(in, ".")
 	return out[0] + extension
 }
 
+// ImportGpxOptions controls how ImportGpx maps GPX waypoint symbols to DirectionCode*
+// constants
+type ImportGpxOptions struct {
+	// Dialect selects the DirectionCodeMapper used for waypoint symbols. When nil,
+	// ImportGpx auto-detects it from the GPX <creator> attribute, falling back to
+	// GPSiesDirectionCodeMapper.
+	Dialect DirectionCodeMapper
+
+	// OnUnsupportedDirectionCode, when set, is called with the raw waypoint symbol for
+	// every code the selected dialect does not recognize, instead of the default
+	// behavior of logging it to stdout.
+	OnUnsupportedDirectionCode func(gpxDirCode string)
+
+	// GenerateTurnsOptions tunes the automatic turn-by-turn waypoints ImportGpx synthesizes
+	// when the source GPX has no waypoints of its own (see GenerateTurns). A nil pointer uses
+	// TurnOptions{}, matching prior behavior.
+	GenerateTurnsOptions *TurnOptions
+
+	// SkipGenerateTurns disables the automatic synthesis entirely, so a caller who wants to
+	// call GenerateTurns itself (e.g. to regenerate with different options later) doesn't end
+	// up with waypoints appended twice.
+	SkipGenerateTurns bool
+}
+
 // ImportGpx file and parse to BrytonData structure
-func (d *BrytonData) ImportGpx(gpxFileName string) error {
+func (d *BrytonData) ImportGpx(gpxFileName string, opts ImportGpxOptions) error {
 
 	fmt.Println("Reading... ", gpxFileName)
 
@@ -207,6 +274,11 @@ func (d *BrytonData) ImportGpx(gpxFileName string) error {
 		return err
 	}
 
+	dialect := opts.Dialect
+	if dialect == nil {
+		dialect = detectDirectionCodeMapper(gpxData.Creator)
+	}
+
 	// smy data
 	d.smy.coordinateCount = int16(gpxData.GetTrackPointsNo())
 	fmt.Printf("Coordinate count: %v\n", d.smy.coordinateCount)
@@ -225,6 +297,7 @@ func (d *BrytonData) ImportGpx(gpxFileName string) error {
 
 			for _, p := range gpxData.Tracks[0].Segments[0].Points {
 				d.track = append(d.track, GeoPoint{adjustGeoCoordinates(p.Point.GetLatitude()), adjustGeoCoordinates(p.Point.GetLongitude())})
+				d.trackTimestamps = append(d.trackTimestamps, p.Timestamp)
 			}
 		}
 	}
@@ -235,7 +308,18 @@ func (d *BrytonData) ImportGpx(gpxFileName string) error {
 	for _, w := range gpxData.Waypoints {
 		var wpt Waypoint
 		wpt.coordinateIndex = d.track.getCoordinateIndex(GeoPoint{adjustGeoCoordinates(w.Point.GetLatitude()), adjustGeoCoordinates(w.Point.GetLongitude())})
-		wpt.directionCode = convertDirectionCode(strings.ToLower(w.Symbol))
+
+		code, extra, ok := dialect.Convert(strings.ToLower(w.Symbol))
+		if !ok {
+			if opts.OnUnsupportedDirectionCode != nil {
+				opts.OnUnsupportedDirectionCode(w.Symbol)
+			} else {
+				fmt.Println("Unsupported direction code: " + w.Symbol + "! Using GoAhead!")
+			}
+			code, extra = DirectionCodeGoAhead, 0
+		}
+		wpt.directionCode = code
+		wpt.extra = extra
 
 		// TODO: should we use these fields?
 		wpt.distance = 0
@@ -246,6 +330,15 @@ func (d *BrytonData) ImportGpx(gpxFileName string) error {
 		d.tinfo = append(d.tinfo, wpt)
 	}
 
+	// No waypoints of its own: synthesize turn-by-turn ones from the track's bearing changes.
+	if len(gpxData.Waypoints) == 0 && !opts.SkipGenerateTurns {
+		turnOpts := TurnOptions{}
+		if opts.GenerateTurnsOptions != nil {
+			turnOpts = *opts.GenerateTurnsOptions
+		}
+		d.GenerateTurns(turnOpts)
+	}
+
 	fmt.Println("...finished in ", -startTimestamp.Sub(time.Now()))
 	return err
 }
@@ -254,31 +347,3 @@ func (d *BrytonData) ImportGpx(gpxFileName string) error {
 func adjustGeoCoordinates(geo float64) int32 {
 	return int32(geo * 1000000.0)
 }
-
-// Convert gpx waypoint direction markers to Bryton compliant.
-// Currently only GPSies.com markers are supported
-func convertDirectionCode(gpxDirCode string) uint8 {
-
-	brytonDirCode := DirectionCodeGoAhead
-
-	switch gpxDirCode {
-	case "tshl":
-		brytonDirCode = DirectionCodeCloseLeft
-	case "left":
-		brytonDirCode = DirectionCodeLeft
-	case "tsll":
-		brytonDirCode = DirectionCodeSlightLeft
-	case "straight":
-		brytonDirCode = DirectionCodeGoAhead
-	case "tslr":
-		brytonDirCode = DirectionCodeSlightRight
-	case "right":
-		brytonDirCode = DirectionCodeRight
-	case "tshr":
-		brytonDirCode = DirectionCodeCloseRight
-	default:
-		fmt.Println("Unsupported direction code: " + gpxDirCode + "! Using GoAhead!")
-	}
-
-	return brytonDirCode
-}
@@ -0,0 +1,150 @@
+package brytongo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DirectionCodeMapper converts a lower-cased GPX waypoint symbol from a particular
+// route-planner dialect into one of the DirectionCode* constants
+type DirectionCodeMapper interface {
+	// Name identifies the dialect, used for <creator> auto-detection
+	Name() string
+
+	// Convert returns the matching DirectionCode* constant for code plus its Waypoint.extra
+	// value (the exit number for DirectionCodeRoundabout, 0 otherwise), or ok=false if this
+	// dialect does not recognize code
+	Convert(code string) (directionCode uint8, extra uint8, ok bool)
+}
+
+// lookupDirectionCodeMapper is a DirectionCodeMapper backed by a flat symbol -> code table
+type lookupDirectionCodeMapper struct {
+	name  string
+	codes map[string]uint8
+}
+
+func (m lookupDirectionCodeMapper) Name() string {
+	return m.name
+}
+
+func (m lookupDirectionCodeMapper) Convert(code string) (uint8, uint8, bool) {
+	directionCode, ok := m.codes[code]
+	return directionCode, 0, ok
+}
+
+// GPSiesDirectionCodeMapper maps GPSies.com waypoint symbols, the module's original and
+// default dialect
+var GPSiesDirectionCodeMapper DirectionCodeMapper = lookupDirectionCodeMapper{
+	name: "GPSies",
+	codes: map[string]uint8{
+		"tshl":     DirectionCodeCloseLeft,
+		"left":     DirectionCodeLeft,
+		"tsll":     DirectionCodeSlightLeft,
+		"straight": DirectionCodeGoAhead,
+		"tslr":     DirectionCodeSlightRight,
+		"right":    DirectionCodeRight,
+		"tshr":     DirectionCodeCloseRight,
+	},
+}
+
+// osmAndRoundaboutExitPrefix prefixes OsmAnd/BRouter's roundabout-exit-N waypoint symbols
+const osmAndRoundaboutExitPrefix = "roundabout-exit-"
+
+// osmAndDirectionCodeMapper maps OsmAnd/BRouter waypoint symbols, additionally parsing
+// roundabout-exit-N into DirectionCodeRoundabout plus its exit number
+type osmAndDirectionCodeMapper struct {
+	lookupDirectionCodeMapper
+}
+
+func (m osmAndDirectionCodeMapper) Convert(code string) (uint8, uint8, bool) {
+	if directionCode, extra, ok := m.lookupDirectionCodeMapper.Convert(code); ok {
+		return directionCode, extra, ok
+	}
+
+	if exit, ok := parseRoundaboutExit(code); ok {
+		return DirectionCodeRoundabout, exit, true
+	}
+
+	return 0, 0, false
+}
+
+// parseRoundaboutExit extracts the exit number N out of a "roundabout-exit-N" symbol
+func parseRoundaboutExit(code string) (uint8, bool) {
+	if !strings.HasPrefix(code, osmAndRoundaboutExitPrefix) {
+		return 0, false
+	}
+
+	exit, err := strconv.Atoi(code[len(osmAndRoundaboutExitPrefix):])
+	if err != nil || exit <= 0 || exit > 0xFF {
+		return 0, false
+	}
+
+	return uint8(exit), true
+}
+
+// OsmAndDirectionCodeMapper maps OsmAnd/BRouter waypoint symbols
+var OsmAndDirectionCodeMapper DirectionCodeMapper = osmAndDirectionCodeMapper{lookupDirectionCodeMapper{
+	name: "OsmAnd",
+	codes: map[string]uint8{
+		"turn":              DirectionCodeGoAhead,
+		"straight":          DirectionCodeGoAhead,
+		"turn-left":         DirectionCodeLeft,
+		"turn-slight-left":  DirectionCodeSlightLeft,
+		"turn-sharp-left":   DirectionCodeCloseLeft,
+		"keep_left":         DirectionCodeSlightLeft,
+		"turn-right":        DirectionCodeRight,
+		"turn-slight-right": DirectionCodeSlightRight,
+		"turn-sharp-right":  DirectionCodeCloseRight,
+		"keep_right":        DirectionCodeSlightRight,
+		"uturn":             DirectionCodeUTurn,
+		"merge":             DirectionCodeMerge,
+		"destination":       DirectionCodeDestinationReached,
+	},
+}}
+
+// KomootDirectionCodeMapper maps komoot.com waypoint symbols
+var KomootDirectionCodeMapper DirectionCodeMapper = lookupDirectionCodeMapper{
+	name: "Komoot",
+	codes: map[string]uint8{
+		"straight":     DirectionCodeGoAhead,
+		"left":         DirectionCodeLeft,
+		"slight-left":  DirectionCodeSlightLeft,
+		"sharp-left":   DirectionCodeCloseLeft,
+		"right":        DirectionCodeRight,
+		"slight-right": DirectionCodeSlightRight,
+		"sharp-right":  DirectionCodeCloseRight,
+	},
+}
+
+// GarminDirectionCodeMapper maps Garmin/Google Maps Routing-style maneuver strings
+// (e.g. TURN_LEFT, TURN_SHARP_RIGHT), matched lower-cased as turn_left, turn_sharp_right
+var GarminDirectionCodeMapper DirectionCodeMapper = lookupDirectionCodeMapper{
+	name: "Garmin",
+	codes: map[string]uint8{
+		"straight":          DirectionCodeGoAhead,
+		"turn_left":         DirectionCodeLeft,
+		"turn_slight_left":  DirectionCodeSlightLeft,
+		"turn_sharp_left":   DirectionCodeCloseLeft,
+		"turn_right":        DirectionCodeRight,
+		"turn_slight_right": DirectionCodeSlightRight,
+		"turn_sharp_right":  DirectionCodeCloseRight,
+	},
+}
+
+// detectDirectionCodeMapper picks a DirectionCodeMapper from a GPX <creator> attribute,
+// falling back to GPSiesDirectionCodeMapper when nothing matches
+func detectDirectionCodeMapper(creator string) DirectionCodeMapper {
+
+	creator = strings.ToLower(creator)
+
+	switch {
+	case strings.Contains(creator, "osmand"), strings.Contains(creator, "brouter"):
+		return OsmAndDirectionCodeMapper
+	case strings.Contains(creator, "komoot"):
+		return KomootDirectionCodeMapper
+	case strings.Contains(creator, "garmin"), strings.Contains(creator, "google"):
+		return GarminDirectionCodeMapper
+	default:
+		return GPSiesDirectionCodeMapper
+	}
+}
@@ -0,0 +1,208 @@
+package brytongo
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// FIT protocol constants used to build a Garmin-compatible .fit course file.
+const (
+	fitProtocolVersion uint8  = 0x10 // FIT protocol version 1.0
+	fitProfileVersion  uint16 = 2078
+	fitHeaderSize      uint8  = 14
+
+	fitBaseTypeEnum   uint8 = 0x00
+	fitBaseTypeSint32 uint8 = 0x85
+	fitBaseTypeUint16 uint8 = 0x8B
+	fitBaseTypeUint32 uint8 = 0x8C
+
+	fitGlobalFileID      uint16 = 0
+	fitGlobalSession     uint16 = 18
+	fitGlobalLap         uint16 = 19
+	fitGlobalRecord      uint16 = 20
+	fitGlobalCourse      uint16 = 31
+	fitGlobalCoursePoint uint16 = 32
+
+	fitFileTypeCourse uint8 = 6
+	fitSportCycling   uint8 = 2
+
+	// fitDistanceScale is the FIT profile's conventional ×100 scale for distance fields
+	// (total_distance, course_point.distance): the wire value is meters×100
+	fitDistanceScale = 100
+
+	fitCoursePointLeft        uint8 = 6
+	fitCoursePointRight       uint8 = 7
+	fitCoursePointStraight    uint8 = 8
+	fitCoursePointSlightLeft  uint8 = 19
+	fitCoursePointSharpLeft   uint8 = 20
+	fitCoursePointSlightRight uint8 = 21
+	fitCoursePointSharpRight  uint8 = 22
+)
+
+// fitCrcTable is the nibble lookup table used by the FIT CRC-16/ARC algorithm.
+var fitCrcTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// fitCrcUpdate folds one byte into a running FIT CRC-16/ARC checksum
+func fitCrcUpdate(crc uint16, b byte) uint16 {
+	tmp := fitCrcTable[crc&0xF]
+	crc = (crc >> 4) & 0x0FFF
+	crc = crc ^ tmp ^ fitCrcTable[b&0xF]
+
+	tmp = fitCrcTable[crc&0xF]
+	crc = (crc >> 4) & 0x0FFF
+	crc = crc ^ tmp ^ fitCrcTable[(b>>4)&0xF]
+
+	return crc
+}
+
+// fitCrc computes the FIT CRC-16/ARC checksum over buf
+func fitCrc(buf []byte) uint16 {
+	var crc uint16
+	for _, b := range buf {
+		crc = fitCrcUpdate(crc, b)
+	}
+	return crc
+}
+
+// semicircles re-scales a Bryton coordinate (1e6-degrees int32) to FIT semicircles (2^31/180)
+func semicircles(geo int32) int32 {
+	return int32(float64(geo) / 1000000.0 * (float64(int64(1)<<31) / 180.0))
+}
+
+// fitFieldDef describes one field of a FIT definition message
+type fitFieldDef struct {
+	num      uint8
+	size     uint8
+	baseType uint8
+}
+
+// writeFitDefinition writes a FIT definition message for globalNum with the given fields
+func writeFitDefinition(buf *bytes.Buffer, localType uint8, globalNum uint16, fields []fitFieldDef) {
+	buf.WriteByte(0x40 | localType) // record header: definition message
+	buf.WriteByte(0x00)             // reserved
+	buf.WriteByte(0x00)             // architecture: little endian
+	binary.Write(buf, binary.LittleEndian, globalNum)
+	buf.WriteByte(uint8(len(fields)))
+
+	for _, field := range fields {
+		buf.WriteByte(field.num)
+		buf.WriteByte(field.size)
+		buf.WriteByte(field.baseType)
+	}
+}
+
+// writeFitData writes a FIT data message header followed by its field values
+func writeFitData(buf *bytes.Buffer, localType uint8, values []interface{}) {
+	buf.WriteByte(localType) // record header: data message
+
+	for _, value := range values {
+		binary.Write(buf, binary.LittleEndian, value)
+	}
+}
+
+// ExportFIT emits a Garmin-compatible .fit course file built from the smy, track and tinfo
+// contents of d, so the same GPX input can drive both Bryton and Garmin/Wahoo/Zwift devices
+func (d *BrytonData) ExportFIT(outFileName string) error {
+
+	var body bytes.Buffer
+
+	// File ID message (local type 0)
+	writeFitDefinition(&body, 0, fitGlobalFileID, []fitFieldDef{
+		{num: 0, size: 1, baseType: fitBaseTypeEnum},   // type
+		{num: 1, size: 2, baseType: fitBaseTypeUint16}, // manufacturer
+		{num: 2, size: 2, baseType: fitBaseTypeUint16}, // product
+	})
+	writeFitData(&body, 0, []interface{}{fitFileTypeCourse, uint16(0xFFFF), uint16(0)})
+
+	// Course message (local type 1)
+	writeFitDefinition(&body, 1, fitGlobalCourse, []fitFieldDef{
+		{num: 4, size: 1, baseType: fitBaseTypeEnum}, // sport
+	})
+	writeFitData(&body, 1, []interface{}{fitSportCycling})
+
+	// Session message (local type 2), bounding box from BrytonSmy
+	writeFitDefinition(&body, 2, fitGlobalSession, []fitFieldDef{
+		{num: 29, size: 4, baseType: fitBaseTypeSint32}, // swc_lat
+		{num: 30, size: 4, baseType: fitBaseTypeSint32}, // swc_long
+		{num: 31, size: 4, baseType: fitBaseTypeSint32}, // nec_lat
+		{num: 32, size: 4, baseType: fitBaseTypeSint32}, // nec_long
+	})
+	writeFitData(&body, 2, []interface{}{
+		semicircles(d.smy.bboxLatSw),
+		semicircles(d.smy.bboxLonSw),
+		semicircles(d.smy.bboxLatNe),
+		semicircles(d.smy.bboxLonNe),
+	})
+
+	// Lap message (local type 3), total distance from BrytonSmy
+	writeFitDefinition(&body, 3, fitGlobalLap, []fitFieldDef{
+		{num: 9, size: 4, baseType: fitBaseTypeUint32}, // total_distance, scale 100
+	})
+	writeFitData(&body, 3, []interface{}{uint32(d.smy.totalDst) * fitDistanceScale})
+
+	// Record messages (local type 4), one per BrytonTrack point
+	writeFitDefinition(&body, 4, fitGlobalRecord, []fitFieldDef{
+		{num: 0, size: 4, baseType: fitBaseTypeSint32}, // position_lat
+		{num: 1, size: 4, baseType: fitBaseTypeSint32}, // position_long
+	})
+	for _, point := range d.track {
+		writeFitData(&body, 4, []interface{}{semicircles(point.lat), semicircles(point.lon)})
+	}
+
+	// CoursePoint messages (local type 5), one per BrytonTinfo waypoint
+	writeFitDefinition(&body, 5, fitGlobalCoursePoint, []fitFieldDef{
+		{num: 2, size: 4, baseType: fitBaseTypeSint32}, // position_lat
+		{num: 3, size: 4, baseType: fitBaseTypeSint32}, // position_long
+		{num: 4, size: 4, baseType: fitBaseTypeUint32}, // distance, scale 100
+		{num: 5, size: 1, baseType: fitBaseTypeEnum},   // type
+	})
+	for _, wpt := range d.tinfo {
+		var lat, lon int32
+		if int(wpt.coordinateIndex) < len(d.track) {
+			lat = semicircles(d.track[wpt.coordinateIndex].lat)
+			lon = semicircles(d.track[wpt.coordinateIndex].lon)
+		}
+
+		writeFitData(&body, 5, []interface{}{lat, lon, uint32(wpt.distance) * fitDistanceScale, convertDirectionCodeToCoursePoint(wpt.directionCode)})
+	}
+
+	var out bytes.Buffer
+
+	header := make([]byte, fitHeaderSize)
+	header[0] = fitHeaderSize
+	header[1] = fitProtocolVersion
+	binary.LittleEndian.PutUint16(header[2:4], fitProfileVersion)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(body.Len()))
+	copy(header[8:12], ".FIT")
+	binary.LittleEndian.PutUint16(header[12:14], fitCrc(header[:12]))
+
+	out.Write(header)
+	out.Write(body.Bytes())
+	binary.Write(&out, binary.LittleEndian, fitCrc(out.Bytes()))
+
+	return storeToFile(out, adjustFilename(outFileName, ".fit"))
+}
+
+// convertDirectionCodeToCoursePoint maps Bryton's DirectionCode* constants to FIT
+// course_point enum values
+func convertDirectionCodeToCoursePoint(code uint8) uint8 {
+	switch code {
+	case DirectionCodeLeft:
+		return fitCoursePointLeft
+	case DirectionCodeRight:
+		return fitCoursePointRight
+	case DirectionCodeSlightLeft:
+		return fitCoursePointSlightLeft
+	case DirectionCodeSlightRight:
+		return fitCoursePointSlightRight
+	case DirectionCodeCloseLeft:
+		return fitCoursePointSharpLeft
+	case DirectionCodeCloseRight:
+		return fitCoursePointSharpRight
+	default:
+		return fitCoursePointStraight
+	}
+}
@@ -0,0 +1,100 @@
+package brytongo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/tkrajina/gpxgo/gpx"
+	"os"
+)
+
+// ExportGpx reconstructs a GPX 1.1 document from d: a track segment built from BrytonTrack
+// and waypoints built from BrytonTinfo, with symbols mapped back from directionCode
+func (d *BrytonData) ExportGpx(outFileName string) error {
+
+	gpxData := new(gpx.GPX)
+	gpxData.Version = "1.1"
+	// convertDirectionCodeToGpxSymbol writes OsmAnd-style symbols; naming it here lets
+	// detectDirectionCodeMapper pick OsmAndDirectionCodeMapper back up on re-import with
+	// default ImportGpxOptions, instead of falling through to GPSies and losing codes.
+	gpxData.Creator = "brytongo (OsmAnd-style symbols)"
+
+	var segment gpx.GPXTrackSegment
+	for _, point := range d.track {
+		segment.Points = append(segment.Points, gpx.GPXPoint{
+			Point: gpx.Point{Latitude: revertGeoCoordinates(point.lat), Longitude: revertGeoCoordinates(point.lon)},
+		})
+	}
+	gpxData.Tracks = append(gpxData.Tracks, gpx.GPXTrack{Segments: []gpx.GPXTrackSegment{segment}})
+
+	for _, wpt := range d.tinfo {
+		var lat, lon float64
+		if int(wpt.coordinateIndex) < len(d.track) {
+			lat = revertGeoCoordinates(d.track[wpt.coordinateIndex].lat)
+			lon = revertGeoCoordinates(d.track[wpt.coordinateIndex].lon)
+		}
+
+		gpxData.Waypoints = append(gpxData.Waypoints, gpx.GPXPoint{
+			Point:  gpx.Point{Latitude: lat, Longitude: lon},
+			Name:   nulTerminatedString(wpt.waypointDescription[:]),
+			Symbol: convertDirectionCodeToGpxSymbol(wpt.directionCode, wpt.extra),
+		})
+	}
+
+	xmlBytes, err := gpxData.ToXml(gpx.ToXmlParams{Version: "1.1", Indent: true})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(adjustFilename(outFileName, ".gpx"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(xmlBytes)
+	return err
+}
+
+// revertGeoCoordinates converts a Bryton compliant int32 coordinate back to float degrees
+func revertGeoCoordinates(geo int32) float64 {
+	return float64(geo) / 1000000.0
+}
+
+// nulTerminatedString trims a fixed-size NUL-padded byte buffer to a Go string
+func nulTerminatedString(b []byte) string {
+	if i := bytes.IndexByte(b, 0x00); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// convertDirectionCodeToGpxSymbol maps Bryton's DirectionCode* constants back to waypoint
+// symbols understood by OsmAndDirectionCodeMapper, so every code (including the
+// roundabout/u-turn/merge/destination ones with no GPSies equivalent) round-trips through
+// ImportGpx by default (extra is the exit number for DirectionCodeRoundabout)
+func convertDirectionCodeToGpxSymbol(code uint8, extra uint8) string {
+	switch code {
+	case DirectionCodeCloseLeft:
+		return "turn-sharp-left"
+	case DirectionCodeLeft:
+		return "turn-left"
+	case DirectionCodeSlightLeft:
+		return "turn-slight-left"
+	case DirectionCodeSlightRight:
+		return "turn-slight-right"
+	case DirectionCodeRight:
+		return "turn-right"
+	case DirectionCodeCloseRight:
+		return "turn-sharp-right"
+	case DirectionCodeRoundabout:
+		return fmt.Sprintf("%s%d", osmAndRoundaboutExitPrefix, extra)
+	case DirectionCodeUTurn:
+		return "uturn"
+	case DirectionCodeMerge:
+		return "merge"
+	case DirectionCodeDestinationReached:
+		return "destination"
+	default:
+		return "straight"
+	}
+}
@@ -0,0 +1,161 @@
+package brytongo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// trackRecordSize is the byte size of one BrytonTrack entry, see BrytonTrack.Export
+const trackRecordSize = 16
+
+// tinfoRecordSize is the byte size of one BrytonTinfo entry, see BrytonTinfo.Export
+const tinfoRecordSize = 44
+
+// loadFile reads the full contents of fileName
+func loadFile(fileName string) ([]byte, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		fmt.Println("Failed to read " + fileName + " error:" + err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ImportSmy reads a .smy file produced by BrytonSmy.Export back into a BrytonSmy structure
+func ImportSmy(fileName string) (BrytonSmy, error) {
+
+	var smy BrytonSmy
+
+	data, err := loadFile(fileName)
+	if err != nil {
+		return smy, err
+	}
+
+	reader := bytes.NewReader(data)
+	layout := []interface{}{&smy.smyFlag, &smy.coordinateCount, &smy.bboxLatNe, &smy.bboxLatSw, &smy.bboxLonNe, &smy.bboxLonSw, &smy.totalDst}
+
+	for _, entry := range layout {
+		if err = binary.Read(reader, binary.LittleEndian, entry); err != nil {
+			return smy, err
+		}
+	}
+
+	// Only the low byte is the magic value; the high byte is a format version (see
+	// smyVersionRoundabout) that older readers are expected to ignore.
+	if int16(uint8(smy.smyFlag)) != smyInitFlag {
+		return smy, fmt.Errorf("unexpected smy flag: 0x%x", smy.smyFlag)
+	}
+
+	return smy, nil
+}
+
+// ImportTrack reads a .track file produced by BrytonTrack.Export back into a BrytonTrack structure
+func ImportTrack(fileName string) (BrytonTrack, error) {
+
+	data, err := loadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data)%trackRecordSize != 0 {
+		return nil, fmt.Errorf("%v is not a valid track file: size %v is not a multiple of %v", fileName, len(data), trackRecordSize)
+	}
+
+	var track BrytonTrack
+	reader := bytes.NewReader(data)
+
+	for reader.Len() > 0 {
+		var point GeoPoint
+		var reserved uint64
+
+		layout := []interface{}{&point.lat, &point.lon, &reserved}
+		for _, entry := range layout {
+			if err = binary.Read(reader, binary.LittleEndian, entry); err != nil {
+				return nil, err
+			}
+		}
+
+		if reserved != 0 {
+			return nil, fmt.Errorf("%v is not a valid track file: reserved bytes are not zero", fileName)
+		}
+
+		track = append(track, point)
+	}
+
+	return track, nil
+}
+
+// ImportTinfo reads a .tinfo file produced by BrytonTinfo.Export back into a BrytonTinfo structure
+func ImportTinfo(fileName string) (BrytonTinfo, error) {
+
+	data, err := loadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data)%tinfoRecordSize != 0 {
+		return nil, fmt.Errorf("%v is not a valid tinfo file: size %v is not a multiple of %v", fileName, len(data), tinfoRecordSize)
+	}
+
+	var tinfo BrytonTinfo
+	reader := bytes.NewReader(data)
+
+	for reader.Len() > 0 {
+		var wpt Waypoint
+		var reservedWord1, reservedWord2 uint16
+
+		layout := []interface{}{&wpt.coordinateIndex, &wpt.directionCode, &wpt.extra, &wpt.distance, &reservedWord1,
+			&wpt.timeSec, &reservedWord2, &wpt.waypointDescription}
+
+		for _, entry := range layout {
+			if err = binary.Read(reader, binary.LittleEndian, entry); err != nil {
+				return nil, err
+			}
+		}
+
+		if reservedWord1 != 0 || reservedWord2 != 0 {
+			return nil, fmt.Errorf("%v is not a valid tinfo file: reserved bytes are not zero", fileName)
+		}
+
+		if bytes.IndexByte(wpt.waypointDescription[:], 0x00) < 0 {
+			return nil, fmt.Errorf("%v is not a valid tinfo file: waypoint description is not NUL-terminated", fileName)
+		}
+
+		tinfo = append(tinfo, wpt)
+	}
+
+	return tinfo, nil
+}
+
+// Import populates d from the .smy, .track and .tinfo files sharing baseName
+func (d *BrytonData) Import(baseName string) error {
+
+	var err error
+
+	if d.smy, err = ImportSmy(adjustFilename(baseName, ".smy")); err != nil {
+		return err
+	}
+
+	if d.track, err = ImportTrack(adjustFilename(baseName, ".track")); err != nil {
+		return err
+	}
+
+	if int(d.smy.coordinateCount) != len(d.track) {
+		return fmt.Errorf("%v: smy coordinate count %v does not match %v track points", baseName, d.smy.coordinateCount, len(d.track))
+	}
+
+	if d.tinfo, err = ImportTinfo(adjustFilename(baseName, ".tinfo")); err != nil {
+		return err
+	}
+
+	for _, wpt := range d.tinfo {
+		if int(wpt.coordinateIndex) >= len(d.track) {
+			return fmt.Errorf("%v: waypoint coordinate index %v is out of range for %v track points", baseName, wpt.coordinateIndex, len(d.track))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package brytongo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBrytonSmyExportImportRoundTrip(t *testing.T) {
+	smy := BrytonSmy{
+		smyFlag:         smyInitFlag,
+		coordinateCount: 2,
+		bboxLatNe:       51700000,
+		bboxLatSw:       51600000,
+		bboxLonNe:       19100000,
+		bboxLonSw:       19000000,
+		totalDst:        1234,
+	}
+
+	base := filepath.Join(t.TempDir(), "route")
+	if err := smy.Export(base); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	got, err := ImportSmy(adjustFilename(base, ".smy"))
+	if err != nil {
+		t.Fatalf("ImportSmy() error = %v", err)
+	}
+
+	if got != smy {
+		t.Errorf("ImportSmy() = %+v, want %+v", got, smy)
+	}
+}
+
+func TestBrytonTrackExportImportRoundTrip(t *testing.T) {
+	track := BrytonTrack{
+		{lat: 51700000, lon: 19100000},
+		{lat: 51650000, lon: 19050000},
+		{lat: 51600000, lon: 19000000},
+	}
+
+	base := filepath.Join(t.TempDir(), "route")
+	if err := track.Export(base); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	got, err := ImportTrack(adjustFilename(base, ".track"))
+	if err != nil {
+		t.Fatalf("ImportTrack() error = %v", err)
+	}
+
+	if len(got) != len(track) {
+		t.Fatalf("ImportTrack() returned %d points, want %d", len(got), len(track))
+	}
+	for i := range track {
+		if got[i] != track[i] {
+			t.Errorf("point %d = %+v, want %+v", i, got[i], track[i])
+		}
+	}
+}
+
+func TestBrytonTinfoExportImportRoundTrip(t *testing.T) {
+	tinfo := BrytonTinfo{}
+	var wpt Waypoint
+	wpt.coordinateIndex = 1
+	wpt.directionCode = DirectionCodeRoundabout
+	wpt.extra = 3
+	wpt.distance = 555
+	wpt.timeSec = 42
+	copy(wpt.waypointDescription[:], "Roundabout exit 3")
+	tinfo = append(tinfo, wpt)
+
+	base := filepath.Join(t.TempDir(), "route")
+	if err := tinfo.Export(base); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	got, err := ImportTinfo(adjustFilename(base, ".tinfo"))
+	if err != nil {
+		t.Fatalf("ImportTinfo() error = %v", err)
+	}
+
+	if len(got) != len(tinfo) {
+		t.Fatalf("ImportTinfo() returned %d waypoints, want %d", len(got), len(tinfo))
+	}
+	if got[0] != tinfo[0] {
+		t.Errorf("ImportTinfo() = %+v, want %+v", got[0], tinfo[0])
+	}
+}
+
+func TestBrytonDataExportImportRoundTrip(t *testing.T) {
+	var d BrytonData
+	d.smy = BrytonSmy{
+		smyFlag:         smyInitFlag,
+		coordinateCount: 2,
+		bboxLatNe:       51700000,
+		bboxLatSw:       51600000,
+		bboxLonNe:       19100000,
+		bboxLonSw:       19000000,
+		totalDst:        1234,
+	}
+	d.track = BrytonTrack{
+		{lat: 51700000, lon: 19100000},
+		{lat: 51600000, lon: 19000000},
+	}
+	var wpt Waypoint
+	wpt.coordinateIndex = 1
+	wpt.directionCode = DirectionCodeLeft
+	d.tinfo = BrytonTinfo{wpt}
+
+	base := filepath.Join(t.TempDir(), "route")
+	d.Export(base)
+
+	var got BrytonData
+	if err := got.Import(base); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if got.smy != d.smy {
+		t.Errorf("smy = %+v, want %+v", got.smy, d.smy)
+	}
+	if len(got.track) != len(d.track) {
+		t.Errorf("track = %+v, want %+v", got.track, d.track)
+	}
+	if len(got.tinfo) != len(d.tinfo) || got.tinfo[0] != d.tinfo[0] {
+		t.Errorf("tinfo = %+v, want %+v", got.tinfo, d.tinfo)
+	}
+}
@@ -0,0 +1,189 @@
+package brytongo
+
+import "math"
+
+// earthRadiusMeters used for Haversine distance
+const earthRadiusMeters = 6371000.0
+
+// DefaultMinSpacingMeters is used when TurnOptions.MinSpacingMeters is left at zero
+const DefaultMinSpacingMeters = 50.0
+
+// Heading-change thresholds (degrees) used to classify a turn, see classifyTurn
+const (
+	turnThresholdSkip   = 15.0
+	turnThresholdSharp  = 110.0
+	turnThresholdSlight = 40.0
+)
+
+// TurnOptions controls how GenerateTurns synthesizes waypoints from a raw GPX track
+type TurnOptions struct {
+	// MinSpacingMeters suppresses turns closer together than this distance along the
+	// track, keeping the larger-magnitude one. Defaults to DefaultMinSpacingMeters when zero.
+	MinSpacingMeters float64
+}
+
+// candidateTurn is a detected heading change at a track index, before spacing dedup
+type candidateTurn struct {
+	index         int
+	directionCode uint8
+	delta         float64
+	distance      float64
+	timeSec       uint16
+}
+
+// GenerateTurns walks d.track and appends Waypoint entries at each significant heading
+// change, for use when a GPX source (unlike curated GPSies routes) carries no waypoints
+// of its own. Waypoint.distance is filled with cumulative Haversine meters along the
+// track, and Waypoint.timeSec from d.trackTimestamps when ImportGpx populated it.
+func (d *BrytonData) GenerateTurns(opts TurnOptions) {
+
+	minSpacing := opts.MinSpacingMeters
+	if minSpacing <= 0 {
+		minSpacing = DefaultMinSpacingMeters
+	}
+
+	if len(d.track) < 3 {
+		return
+	}
+
+	haveTimestamps := len(d.trackTimestamps) == len(d.track)
+
+	cumulative := make([]float64, len(d.track))
+	for i := 1; i < len(d.track); i++ {
+		cumulative[i] = cumulative[i-1] + haversineMeters(d.track[i-1], d.track[i])
+	}
+
+	var candidates []candidateTurn
+
+	for i := 1; i < len(d.track)-1; i++ {
+		bearingIn := initialBearing(d.track[i-1], d.track[i])
+		bearingOut := initialBearing(d.track[i], d.track[i+1])
+		delta := turnAngle(bearingIn, bearingOut)
+
+		code, ok := classifyTurn(delta)
+		if !ok {
+			continue
+		}
+
+		candidate := candidateTurn{index: i, directionCode: code, delta: delta, distance: cumulative[i]}
+
+		if haveTimestamps && !d.trackTimestamps[i].IsZero() && !d.trackTimestamps[0].IsZero() {
+			candidate.timeSec = uint16(d.trackTimestamps[i].Sub(d.trackTimestamps[0]).Seconds())
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	var turns []candidateTurn
+	for _, candidate := range candidates {
+		if len(turns) > 0 && candidate.distance-turns[len(turns)-1].distance < minSpacing {
+			if math.Abs(candidate.delta) > math.Abs(turns[len(turns)-1].delta) {
+				turns[len(turns)-1] = candidate
+			}
+			continue
+		}
+
+		turns = append(turns, candidate)
+	}
+
+	for _, turn := range turns {
+		var wpt Waypoint
+		wpt.coordinateIndex = uint16(turn.index)
+		wpt.directionCode = turn.directionCode
+		wpt.distance = clampToUint16(turn.distance)
+		wpt.timeSec = turn.timeSec
+		copy(wpt.waypointDescription[:], turnLabel(turn.directionCode))
+
+		d.tinfo = append(d.tinfo, wpt)
+	}
+}
+
+// classifyTurn buckets a signed heading change (degrees, positive = clockwise/right) into
+// one of the existing DirectionCode* constants. Reports ok=false for changes too small
+// to be worth a waypoint.
+func classifyTurn(delta float64) (code uint8, ok bool) {
+
+	magnitude := math.Abs(delta)
+	right := delta > 0
+
+	switch {
+	case magnitude < turnThresholdSkip:
+		return 0, false
+	case magnitude <= turnThresholdSlight:
+		if right {
+			return DirectionCodeSlightRight, true
+		}
+		return DirectionCodeSlightLeft, true
+	case magnitude <= turnThresholdSharp:
+		if right {
+			return DirectionCodeRight, true
+		}
+		return DirectionCodeLeft, true
+	default:
+		if right {
+			return DirectionCodeCloseRight, true
+		}
+		return DirectionCodeCloseLeft, true
+	}
+}
+
+// turnLabel returns a short generated waypoint description for code. Left blank (no
+// street name source is wired) beyond the turn direction itself.
+func turnLabel(code uint8) string {
+	switch code {
+	case DirectionCodeLeft, DirectionCodeCloseLeft:
+		return "Turn left"
+	case DirectionCodeSlightLeft:
+		return "Turn slightly left"
+	case DirectionCodeRight, DirectionCodeCloseRight:
+		return "Turn right"
+	case DirectionCodeSlightRight:
+		return "Turn slightly right"
+	default:
+		return ""
+	}
+}
+
+// initialBearing returns the initial compass bearing (degrees, 0=north, clockwise) of
+// the great-circle path from one point to the other
+func initialBearing(from, to GeoPoint) float64 {
+	lat1 := degToRad(revertGeoCoordinates(from.lat))
+	lat2 := degToRad(revertGeoCoordinates(to.lat))
+	deltaLon := degToRad(revertGeoCoordinates(to.lon) - revertGeoCoordinates(from.lon))
+
+	y := math.Sin(deltaLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(deltaLon)
+
+	return radToDeg(math.Atan2(y, x))
+}
+
+// turnAngle returns the signed turn (degrees, -180..180, positive = clockwise/right)
+// between two compass bearings
+func turnAngle(bearingIn, bearingOut float64) float64 {
+	return math.Mod(bearingOut-bearingIn+540, 360) - 180
+}
+
+// haversineMeters returns the great-circle distance between two points in meters
+func haversineMeters(from, to GeoPoint) float64 {
+	lat1 := degToRad(revertGeoCoordinates(from.lat))
+	lat2 := degToRad(revertGeoCoordinates(to.lat))
+	deltaLat := degToRad(revertGeoCoordinates(to.lat) - revertGeoCoordinates(from.lat))
+	deltaLon := degToRad(revertGeoCoordinates(to.lon) - revertGeoCoordinates(from.lon))
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusMeters * c
+}
+
+func degToRad(deg float64) float64 { return deg * math.Pi / 180.0 }
+func radToDeg(rad float64) float64 { return rad * 180.0 / math.Pi }
+
+// clampToUint16 saturates meters at math.MaxUint16 instead of silently wrapping, since
+// Waypoint.distance can't represent routes longer than 65.535km
+func clampToUint16(meters float64) uint16 {
+	if meters >= math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return uint16(meters)
+}
@@ -0,0 +1,59 @@
+package brytongo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassifyTurn(t *testing.T) {
+	tests := []struct {
+		name     string
+		delta    float64
+		wantCode uint8
+		wantOk   bool
+	}{
+		{"straight ahead", 5, 0, false},
+		{"just under skip threshold", 14.9, 0, false},
+		{"slight right", 30, DirectionCodeSlightRight, true},
+		{"slight left", -30, DirectionCodeSlightLeft, true},
+		{"right", 90, DirectionCodeRight, true},
+		{"left", -90, DirectionCodeLeft, true},
+		{"sharp right", 150, DirectionCodeCloseRight, true},
+		{"sharp left", -150, DirectionCodeCloseLeft, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := classifyTurn(tt.delta)
+			if ok != tt.wantOk {
+				t.Fatalf("classifyTurn(%v) ok = %v, want %v", tt.delta, ok, tt.wantOk)
+			}
+			if ok && code != tt.wantCode {
+				t.Errorf("classifyTurn(%v) code = %v, want %v", tt.delta, code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestInitialBearing(t *testing.T) {
+	tests := []struct {
+		name string
+		from GeoPoint
+		to   GeoPoint
+		want float64
+	}{
+		{"due north", GeoPoint{lat: 0, lon: 0}, GeoPoint{lat: 1000000, lon: 0}, 0},
+		{"due east", GeoPoint{lat: 0, lon: 0}, GeoPoint{lat: 0, lon: 1000000}, 90},
+		{"due south", GeoPoint{lat: 1000000, lon: 0}, GeoPoint{lat: 0, lon: 0}, 180},
+		{"due west", GeoPoint{lat: 0, lon: 1000000}, GeoPoint{lat: 0, lon: 0}, 270},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := math.Mod(initialBearing(tt.from, tt.to)+360, 360)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("initialBearing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}